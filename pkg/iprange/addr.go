@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"fmt"
+	"iter"
+	"math/big"
+	"net"
+	"net/netip"
+)
+
+// AddrRange is an IP address range built on net/netip.Addr. Unlike Range,
+// it is a value type: comparable, allocation-free, and safe to embed
+// directly in module configuration structs.
+type AddrRange struct {
+	first netip.Addr
+	last  netip.Addr
+}
+
+// NewAddrRange returns the AddrRange [first, last]. ok is false if first and
+// last are not of the same address family, or first is after last.
+func NewAddrRange(first, last netip.Addr) (r AddrRange, ok bool) {
+	first, last = first.Unmap(), last.Unmap()
+	if !first.IsValid() || !last.IsValid() {
+		return AddrRange{}, false
+	}
+	if first.Is4() != last.Is4() {
+		return AddrRange{}, false
+	}
+	if first.Compare(last) > 0 {
+		return AddrRange{}, false
+	}
+	return AddrRange{first: first, last: last}, true
+}
+
+// First returns the first address of the range.
+func (r AddrRange) First() netip.Addr { return r.first }
+
+// Last returns the last address of the range.
+func (r AddrRange) Last() netip.Addr { return r.last }
+
+// Contains reports whether the range includes addr.
+func (r AddrRange) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.Is4() != r.first.Is4() {
+		return false
+	}
+	return addr.Compare(r.first) >= 0 && addr.Compare(r.last) <= 0
+}
+
+// String returns the string form of the range.
+func (r AddrRange) String() string {
+	return r.first.String() + "-" + r.last.String()
+}
+
+// Prefix returns the CIDR that exactly covers the range. ok is false unless
+// the range is exactly one CIDR block.
+func (r AddrRange) Prefix() (netip.Prefix, bool) {
+	prefixes := r.Prefixes()
+	if len(prefixes) == 1 {
+		return prefixes[0], true
+	}
+	return netip.Prefix{}, false
+}
+
+// Prefixes decomposes the range into the minimal set of CIDR blocks that
+// cover it.
+func (r AddrRange) Prefixes() []netip.Prefix {
+	bits := r.first.BitLen()
+	cur := new(big.Int).SetBytes(r.first.AsSlice())
+	last := new(big.Int).SetBytes(r.last.AsSlice())
+	one := big.NewInt(1)
+
+	var prefixes []netip.Prefix
+	for cur.Cmp(last) <= 0 {
+		hostBits := trailingZeroBits(cur, bits)
+		for hostBits > 0 {
+			blockEnd := new(big.Int).Lsh(one, uint(hostBits))
+			blockEnd.Add(blockEnd, cur)
+			blockEnd.Sub(blockEnd, one)
+			if blockEnd.Cmp(last) <= 0 {
+				break
+			}
+			hostBits--
+		}
+
+		prefixes = append(prefixes, netip.PrefixFrom(addrFromBig(cur, bits), bits-hostBits))
+		cur.Add(cur, new(big.Int).Lsh(one, uint(hostBits)))
+	}
+
+	return prefixes
+}
+
+// Iter returns a range-over-func iterator that walks every address in the
+// range in order, using Addr.Next() rather than allocating a *big.Int per
+// step.
+func (r AddrRange) Iter() iter.Seq[netip.Addr] {
+	return func(yield func(netip.Addr) bool) {
+		for a := r.first; ; a = a.Next() {
+			if !yield(a) {
+				return
+			}
+			if a == r.last {
+				return
+			}
+		}
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r AddrRange) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting the same
+// formats as ParseRange.
+func (r *AddrRange) UnmarshalText(text []byte) error {
+	rng, err := ParseRange(string(text))
+	if err != nil {
+		return err
+	}
+	ar, ok := FromRange(rng)
+	if !ok {
+		return fmt.Errorf("iprange: invalid address range '%s'", text)
+	}
+	*r = ar
+	return nil
+}
+
+// ToRange converts r to the equivalent net.IP-based Range.
+func (r AddrRange) ToRange() Range {
+	return New(net.IP(r.first.AsSlice()), net.IP(r.last.AsSlice()))
+}
+
+// FromRange converts a net.IP-based Range to an AddrRange. ok is false if r
+// is nil or holds addresses netip cannot represent.
+func FromRange(r Range) (AddrRange, bool) {
+	if r == nil {
+		return AddrRange{}, false
+	}
+	first, ok1 := netip.AddrFromSlice(r.Start())
+	last, ok2 := netip.AddrFromSlice(r.End())
+	if !ok1 || !ok2 {
+		return AddrRange{}, false
+	}
+	return NewAddrRange(first, last)
+}
+
+func addrFromBig(n *big.Int, bits int) netip.Addr {
+	b := n.Bytes()
+	buf := make([]byte, bits/8)
+	copy(buf[len(buf)-len(b):], b)
+	addr, _ := netip.AddrFromSlice(buf)
+	return addr
+}
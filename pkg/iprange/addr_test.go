@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustAddr(t *testing.T, s string) netip.Addr {
+	t.Helper()
+	addr, err := netip.ParseAddr(s)
+	require.NoError(t, err)
+	return addr
+}
+
+func TestNewAddrRange(t *testing.T) {
+	tests := map[string]struct {
+		first, last string
+		wantOK      bool
+	}{
+		"v4 ok":               {first: "192.0.2.0", last: "192.0.2.10", wantOK: true},
+		"v6 ok":               {first: "2001:db8::", last: "2001:db8::ff", wantOK: true},
+		"single address":      {first: "192.0.2.5", last: "192.0.2.5", wantOK: true},
+		"mismatched families": {first: "192.0.2.0", last: "2001:db8::1", wantOK: false},
+		"first after last":    {first: "192.0.2.10", last: "192.0.2.0", wantOK: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			first, last := mustAddr(t, test.first), mustAddr(t, test.last)
+			r, ok := NewAddrRange(first, last)
+			assert.Equal(t, test.wantOK, ok)
+			if test.wantOK {
+				assert.Equal(t, first, r.First())
+				assert.Equal(t, last, r.Last())
+			}
+		})
+	}
+}
+
+func TestNewAddrRange_InvalidAddr(t *testing.T) {
+	_, ok := NewAddrRange(netip.Addr{}, mustAddr(t, "192.0.2.0"))
+	assert.False(t, ok)
+}
+
+func TestAddrRange_Contains(t *testing.T) {
+	tests := map[string]struct {
+		first, last, addr string
+		want              bool
+	}{
+		"v4 hit at lower boundary": {first: "192.0.2.0", last: "192.0.2.10", addr: "192.0.2.0", want: true},
+		"v4 hit at upper boundary": {first: "192.0.2.0", last: "192.0.2.10", addr: "192.0.2.10", want: true},
+		"v4 miss just below":       {first: "192.0.2.1", last: "192.0.2.10", addr: "192.0.2.0", want: false},
+		"v4 miss just above":       {first: "192.0.2.0", last: "192.0.2.10", addr: "192.0.2.11", want: false},
+		"v6 hit at lower boundary": {first: "2001:db8::", last: "2001:db8::ff", addr: "2001:db8::", want: true},
+		"v6 hit at upper boundary": {first: "2001:db8::", last: "2001:db8::ff", addr: "2001:db8::ff", want: true},
+		"v6 miss":                  {first: "2001:db8::", last: "2001:db8::ff", addr: "2001:db8::100", want: false},
+		"cross-family miss":        {first: "192.0.2.0", last: "192.0.2.10", addr: "2001:db8::1", want: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, ok := NewAddrRange(mustAddr(t, test.first), mustAddr(t, test.last))
+			require.True(t, ok)
+			assert.Equal(t, test.want, r.Contains(mustAddr(t, test.addr)))
+		})
+	}
+}
+
+func TestAddrRange_Prefix(t *testing.T) {
+	tests := map[string]struct {
+		first, last string
+		wantOK      bool
+	}{
+		"exact v4 /24":    {first: "192.0.2.0", last: "192.0.2.255", wantOK: true},
+		"exact v6 /127":   {first: "2001:db8::", last: "2001:db8::1", wantOK: true},
+		"unaligned range": {first: "192.0.2.0", last: "192.0.2.10", wantOK: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, ok := NewAddrRange(mustAddr(t, test.first), mustAddr(t, test.last))
+			require.True(t, ok)
+
+			_, gotOK := r.Prefix()
+			assert.Equal(t, test.wantOK, gotOK)
+		})
+	}
+}
+
+func TestAddrRange_Prefixes(t *testing.T) {
+	tests := map[string]struct {
+		first, last string
+		want        []string
+	}{
+		"unaligned v4 range": {
+			first: "192.0.2.0", last: "192.0.2.10",
+			want: []string{"192.0.2.0/29", "192.0.2.8/31", "192.0.2.10/32"},
+		},
+		"single address": {
+			first: "192.0.2.5", last: "192.0.2.5",
+			want: []string{"192.0.2.5/32"},
+		},
+		"full /24 block": {
+			first: "192.0.2.0", last: "192.0.2.255",
+			want: []string{"192.0.2.0/24"},
+		},
+		"unaligned v6 range": {
+			first: "2001:db8::", last: "2001:db8::2",
+			want: []string{"2001:db8::/127", "2001:db8::2/128"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, ok := NewAddrRange(mustAddr(t, test.first), mustAddr(t, test.last))
+			require.True(t, ok)
+
+			var got []string
+			for _, p := range r.Prefixes() {
+				got = append(got, p.String())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestAddrRange_Iter(t *testing.T) {
+	r, ok := NewAddrRange(mustAddr(t, "192.0.2.0"), mustAddr(t, "192.0.2.4"))
+	require.True(t, ok)
+
+	want := []string{"192.0.2.0", "192.0.2.1", "192.0.2.2", "192.0.2.3", "192.0.2.4"}
+
+	var got []string
+	r.Iter()(func(addr netip.Addr) bool {
+		got = append(got, addr.String())
+		return true
+	})
+	assert.Equal(t, want, got)
+}
+
+func TestAddrRange_Iter_NoWrapAtFamilyMax(t *testing.T) {
+	r, ok := NewAddrRange(mustAddr(t, "255.255.255.254"), mustAddr(t, "255.255.255.255"))
+	require.True(t, ok)
+
+	var got []string
+	r.Iter()(func(addr netip.Addr) bool {
+		got = append(got, addr.String())
+		return true
+	})
+	assert.Equal(t, []string{"255.255.255.254", "255.255.255.255"}, got)
+}
+
+func TestAddrRange_Iter_StopsEarly(t *testing.T) {
+	r, ok := NewAddrRange(mustAddr(t, "192.0.2.0"), mustAddr(t, "192.0.2.10"))
+	require.True(t, ok)
+
+	var got []string
+	r.Iter()(func(addr netip.Addr) bool {
+		got = append(got, addr.String())
+		return addr.String() != "192.0.2.1"
+	})
+	assert.Equal(t, []string{"192.0.2.0", "192.0.2.1"}, got)
+}
+
+func TestAddrRange_MarshalUnmarshalText(t *testing.T) {
+	tests := []string{"192.0.2.0-192.0.2.10", "2001:db8::-2001:db8::ff"}
+
+	for _, s := range tests {
+		t.Run(s, func(t *testing.T) {
+			var r AddrRange
+			require.NoError(t, r.UnmarshalText([]byte(s)))
+
+			text, err := r.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, s, string(text))
+		})
+	}
+}
+
+func TestAddrRange_UnmarshalText_Invalid(t *testing.T) {
+	var r AddrRange
+	assert.Error(t, r.UnmarshalText([]byte("not-an-ip")))
+}
+
+func TestAddrRange_ToFromRange(t *testing.T) {
+	want, ok := NewAddrRange(mustAddr(t, "192.0.2.0"), mustAddr(t, "192.0.2.10"))
+	require.True(t, ok)
+
+	got, ok := FromRange(want.ToRange())
+	require.True(t, ok)
+	assert.Equal(t, want, got)
+}
+
+func TestFromRange_Nil(t *testing.T) {
+	_, ok := FromRange(nil)
+	assert.False(t, ok)
+}
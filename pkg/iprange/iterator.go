@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+)
+
+// MaxEnumerable is the largest range size, in addresses, that is safe to
+// enumerate into a bitset-backed or slice-backed collection. A range's
+// Size() can exceed this (e.g. an IPv6 /48), but callers that need to
+// materialize every address should check EnumerableSize() first.
+const MaxEnumerable = math.MaxUint32
+
+// Iterator walks the addresses of a Range in order, in O(1) memory.
+// The zero value is not usable; obtain an Iterator via Range.Iterator().
+type Iterator struct {
+	r    Range
+	cur  net.IP
+	done bool
+}
+
+func newIterator(r Range) Iterator {
+	return Iterator{r: r}
+}
+
+// Next returns the next IP address in the range, or nil once the range is
+// exhausted.
+func (it *Iterator) Next() net.IP {
+	if it.done {
+		return nil
+	}
+
+	if it.cur == nil {
+		it.cur = append(net.IP(nil), it.r.Start()...)
+		return append(net.IP(nil), it.cur...)
+	}
+
+	next := nextIP(it.cur)
+	if next == nil || bytes.Compare(normalizeIP(next), normalizeIP(it.r.End())) > 0 {
+		it.done = true
+		return nil
+	}
+	it.cur = next
+	return append(net.IP(nil), it.cur...)
+}
+
+// Reset rewinds the iterator back to the start of the range.
+func (it *Iterator) Reset() {
+	it.cur = nil
+	it.done = false
+}
+
+// enumerableSize returns size as a uint64, or an error if it exceeds
+// MaxEnumerable.
+func enumerableSize(size *big.Int) (uint64, error) {
+	if !size.IsUint64() || size.Uint64() > MaxEnumerable {
+		return 0, fmt.Errorf("iprange: range size %s exceeds MaxEnumerable (%d)", size, uint64(MaxEnumerable))
+	}
+	return size.Uint64(), nil
+}
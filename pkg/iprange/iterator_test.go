@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator_NextReset(t *testing.T) {
+	r := New(net.ParseIP("192.0.2.0"), net.ParseIP("192.0.2.4"))
+	require.NotNil(t, r)
+	want := []string{"192.0.2.0", "192.0.2.1", "192.0.2.2", "192.0.2.3", "192.0.2.4"}
+
+	it := r.Iterator()
+
+	var got []string
+	for {
+		ip := it.Next()
+		if ip == nil {
+			break
+		}
+		got = append(got, ip.String())
+	}
+	assert.Equal(t, want, got)
+
+	// Exhausted iterators keep returning nil.
+	assert.Nil(t, it.Next())
+
+	it.Reset()
+	got = got[:0]
+	for {
+		ip := it.Next()
+		if ip == nil {
+			break
+		}
+		got = append(got, ip.String())
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestIterator_NoWrapAtFamilyMax(t *testing.T) {
+	r := New(net.ParseIP("255.255.255.254"), net.ParseIP("255.255.255.255"))
+	require.NotNil(t, r)
+
+	it := r.Iterator()
+	assert.Equal(t, "255.255.255.254", it.Next().String())
+	assert.Equal(t, "255.255.255.255", it.Next().String())
+	assert.Nil(t, it.Next(), "iterator must stop at the family's highest address, not wrap to 0.0.0.0")
+}
+
+func TestRange_EnumerableSize(t *testing.T) {
+	tests := map[string]struct {
+		start, end string
+		wantErr    bool
+		wantSize   uint64
+	}{
+		"v4 /24 is enumerable": {
+			start: "192.0.2.0", end: "192.0.2.255",
+			wantSize: 256,
+		},
+		"v6 /48 exceeds MaxEnumerable": {
+			start: "2001:db8::", end: "2001:db8:0:ffff:ffff:ffff:ffff:ffff",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := New(net.ParseIP(test.start), net.ParseIP(test.end))
+			require.NotNil(t, r)
+
+			n, err := r.EnumerableSize()
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantSize, n)
+		})
+	}
+}
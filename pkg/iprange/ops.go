@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import "bytes"
+
+// Intersects reports whether a and b share at least one address. It always
+// returns false for ranges of different address families.
+func Intersects(a, b Range) bool {
+	if a.Family() != b.Family() {
+		return false
+	}
+	return bytes.Compare(normalizeIP(a.Start()), normalizeIP(b.End())) <= 0 &&
+		bytes.Compare(normalizeIP(b.Start()), normalizeIP(a.End())) <= 0
+}
+
+// Adjacent reports whether a and b sit back-to-back with no gap and no
+// overlap between them, i.e. a.End()+1 == b.Start() or vice versa. It always
+// returns false for ranges of different address families.
+func Adjacent(a, b Range) bool {
+	if a.Family() != b.Family() {
+		return false
+	}
+	if next := nextIP(a.End()); next != nil && bytes.Equal(normalizeIP(next), normalizeIP(b.Start())) {
+		return true
+	}
+	if next := nextIP(b.End()); next != nil && bytes.Equal(normalizeIP(next), normalizeIP(a.Start())) {
+		return true
+	}
+	return false
+}
+
+// Contiguous reports whether a and b can be merged into a single range,
+// i.e. whether they intersect or are adjacent.
+func Contiguous(a, b Range) bool {
+	return Intersects(a, b) || Adjacent(a, b)
+}
+
+// Join returns the range spanning min(a.Start(), b.Start()) to
+// max(a.End(), b.End()) if a and b are contiguous. Otherwise it returns nil.
+func Join(a, b Range) Range {
+	if !Contiguous(a, b) {
+		return nil
+	}
+	return New(minIP(a.Start(), b.Start()), maxIP(a.End(), b.End()))
+}
+
+// Subtract returns a with the addresses of b removed, as zero, one, or two
+// ranges depending on how b overlaps a. If a and b are not contiguous (or
+// are of different address families), Subtract returns a unchanged.
+func Subtract(a, b Range) []Range {
+	if !Contiguous(a, b) {
+		return []Range{a}
+	}
+
+	var out []Range
+	if bytes.Compare(normalizeIP(b.Start()), normalizeIP(a.Start())) > 0 {
+		if prev := prevIP(b.Start()); prev != nil {
+			if before := New(a.Start(), prev); before != nil {
+				out = append(out, before)
+			}
+		}
+	}
+	if bytes.Compare(normalizeIP(b.End()), normalizeIP(a.End())) < 0 {
+		if next := nextIP(b.End()); next != nil {
+			if after := New(next, a.End()); after != nil {
+				out = append(out, after)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntersects(t *testing.T) {
+	v4 := mustParseRange(t, "10.0.0.0-10.0.0.10")
+	v6 := mustParseRange(t, "2001:db8::-2001:db8::ff")
+
+	tests := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"overlapping":          {a: "10.0.0.0-10.0.0.10", b: "10.0.0.5-10.0.0.20", want: true},
+		"adjacent, no overlap": {a: "10.0.0.0-10.0.0.10", b: "10.0.0.11-10.0.0.20", want: false},
+		"disjoint":             {a: "10.0.0.0-10.0.0.10", b: "10.0.0.20-10.0.0.30", want: false},
+		"fully contained":      {a: "10.0.0.0-10.0.0.30", b: "10.0.0.10-10.0.0.20", want: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, b := mustParseRange(t, test.a), mustParseRange(t, test.b)
+			assert.Equal(t, test.want, Intersects(a, b))
+		})
+	}
+
+	assert.False(t, Intersects(v4, v6), "cross-family ranges must never intersect")
+}
+
+func TestAdjacent(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"adjacent, a before b":        {a: "10.0.0.0-10.0.0.10", b: "10.0.0.11-10.0.0.20", want: true},
+		"adjacent, b before a":        {a: "10.0.0.11-10.0.0.20", b: "10.0.0.0-10.0.0.10", want: true},
+		"overlapping is not adjacent": {a: "10.0.0.0-10.0.0.10", b: "10.0.0.5-10.0.0.20", want: false},
+		"disjoint with a gap":         {a: "10.0.0.0-10.0.0.10", b: "10.0.0.12-10.0.0.20", want: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, b := mustParseRange(t, test.a), mustParseRange(t, test.b)
+			assert.Equal(t, test.want, Adjacent(a, b))
+		})
+	}
+
+	v4 := mustParseRange(t, "10.0.0.0-10.0.0.10")
+	v6 := mustParseRange(t, "2001:db8::-2001:db8::ff")
+	assert.False(t, Adjacent(v4, v6), "cross-family ranges must never be adjacent")
+}
+
+func TestContiguous(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"overlapping": {a: "10.0.0.0-10.0.0.10", b: "10.0.0.5-10.0.0.20", want: true},
+		"adjacent":    {a: "10.0.0.0-10.0.0.10", b: "10.0.0.11-10.0.0.20", want: true},
+		"disjoint":    {a: "10.0.0.0-10.0.0.10", b: "10.0.0.20-10.0.0.30", want: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, b := mustParseRange(t, test.a), mustParseRange(t, test.b)
+			assert.Equal(t, test.want, Contiguous(a, b))
+		})
+	}
+
+	v4 := mustParseRange(t, "10.0.0.0-10.0.0.10")
+	v6 := mustParseRange(t, "2001:db8::-2001:db8::ff")
+	assert.False(t, Contiguous(v4, v6), "cross-family ranges must never be contiguous")
+}
+
+func TestJoin(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want string
+	}{
+		"overlapping": {a: "10.0.0.0-10.0.0.10", b: "10.0.0.5-10.0.0.20", want: "10.0.0.0-10.0.0.20"},
+		"adjacent":    {a: "10.0.0.0-10.0.0.10", b: "10.0.0.11-10.0.0.20", want: "10.0.0.0-10.0.0.20"},
+		"disjoint":    {a: "10.0.0.0-10.0.0.10", b: "10.0.0.20-10.0.0.30", want: ""},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, b := mustParseRange(t, test.a), mustParseRange(t, test.b)
+			got := Join(a, b)
+			if test.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, test.want, got.String())
+		})
+	}
+
+	v4 := mustParseRange(t, "10.0.0.0-10.0.0.10")
+	v6 := mustParseRange(t, "2001:db8::-2001:db8::ff")
+	assert.Nil(t, Join(v4, v6), "cross-family ranges must never join")
+}
+
+func TestSubtract(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want []string
+	}{
+		"b fully covers a returns zero ranges": {
+			a: "10.0.0.10-10.0.0.20", b: "10.0.0.0-10.0.0.30",
+			want: nil,
+		},
+		"b in the middle returns two ranges": {
+			a: "10.0.0.0-10.0.0.30", b: "10.0.0.10-10.0.0.20",
+			want: []string{"10.0.0.0-10.0.0.9", "10.0.0.21-10.0.0.30"},
+		},
+		"b overlaps the start edge returns one range": {
+			a: "10.0.0.10-10.0.0.30", b: "10.0.0.0-10.0.0.20",
+			want: []string{"10.0.0.21-10.0.0.30"},
+		},
+		"b overlaps the end edge returns one range": {
+			a: "10.0.0.10-10.0.0.30", b: "10.0.0.20-10.0.0.40",
+			want: []string{"10.0.0.10-10.0.0.19"},
+		},
+		"b disjoint but adjacent leaves a untouched": {
+			a: "10.0.0.0-10.0.0.10", b: "10.0.0.11-10.0.0.20",
+			want: []string{"10.0.0.0-10.0.0.10"},
+		},
+		"b disjoint with a gap leaves a untouched": {
+			a: "10.0.0.0-10.0.0.10", b: "10.0.0.20-10.0.0.30",
+			want: []string{"10.0.0.0-10.0.0.10"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, b := mustParseRange(t, test.a), mustParseRange(t, test.b)
+			got := Subtract(a, b)
+
+			var gotStr []string
+			for _, r := range got {
+				gotStr = append(gotStr, r.String())
+			}
+			assert.Equal(t, test.want, gotStr)
+		})
+	}
+
+	v4 := mustParseRange(t, "10.0.0.0-10.0.0.10")
+	v6 := mustParseRange(t, "2001:db8::-2001:db8::ff")
+	assert.Equal(t, []Range{v4}, Subtract(v4, v6), "cross-family Subtract must return a unchanged")
+}
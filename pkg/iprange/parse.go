@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ParseRange parses s and returns the corresponding Range.
+// Accepted formats:
+//   - single address: "192.0.2.1", "2001:db8::1"
+//   - start-end: "192.0.2.0-192.0.2.10"
+//   - shorthand end: "192.0.2.1-10" (the token after "-" replaces the
+//     same number of trailing octets/groups of start), "2001:db8::1-a"
+//   - IPv4 CIDR: "192.0.2.0/24"
+//   - IPv4 dotted subnet mask: "192.0.2.0/255.255.255.0"
+//   - IPv6 CIDR: "2001:db8::/64"
+//
+// IPv4 CIDR and subnet-mask forms exclude the network and broadcast
+// addresses, except for /31 and /32 which keep both. IPv6 CIDR keeps all
+// addresses.
+func ParseRange(s string) (Range, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("iprange: empty range")
+	}
+
+	if idx := strings.IndexByte(s, '/'); idx != -1 {
+		return parseCIDR(s[:idx], s[idx+1:])
+	}
+
+	if idx := strings.IndexByte(s, '-'); idx != -1 {
+		return parseStartEnd(s, s[:idx], s[idx+1:])
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("iprange: invalid address '%s'", s)
+	}
+	if r := New(ip, ip); r != nil {
+		return r, nil
+	}
+	return nil, fmt.Errorf("iprange: invalid address '%s'", s)
+}
+
+// ParseList parses a comma- and/or whitespace-separated list of ranges.
+func ParseList(s string) ([]Range, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+
+	var ranges []Range
+	for _, f := range fields {
+		r, err := ParseRange(f)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+func parseStartEnd(full, startStr, endToken string) (Range, error) {
+	start := net.ParseIP(startStr)
+	if start == nil {
+		return nil, fmt.Errorf("iprange: invalid range '%s'", full)
+	}
+
+	sep := "."
+	if strings.Contains(startStr, ":") {
+		sep = ":"
+	}
+
+	var end net.IP
+	if strings.Contains(endToken, sep) {
+		end = net.ParseIP(endToken)
+		if end == nil {
+			return nil, fmt.Errorf("iprange: invalid range '%s'", full)
+		}
+	} else {
+		var err error
+		end, err = shorthandEnd(start, endToken, sep)
+		if err != nil {
+			return nil, fmt.Errorf("iprange: invalid range '%s'", full)
+		}
+	}
+
+	r := New(start, end)
+	if r == nil {
+		return nil, fmt.Errorf("iprange: invalid range '%s'", full)
+	}
+	return r, nil
+}
+
+// shorthandEnd builds the end address of a shorthand range by replacing the
+// trailing octets (IPv4) or groups (IPv6) of start with those parsed from
+// token, keeping the leading octets/groups of start unchanged.
+func shorthandEnd(start net.IP, token, sep string) (net.IP, error) {
+	tokenParts := strings.Split(token, sep)
+
+	if sep == ":" {
+		groups := expandV6Groups(start)
+		if len(tokenParts) > len(groups) {
+			return nil, fmt.Errorf("iprange: invalid shorthand end '%s'", token)
+		}
+		copy(groups[len(groups)-len(tokenParts):], tokenParts)
+		end := net.ParseIP(strings.Join(groups, sep))
+		if end == nil {
+			return nil, fmt.Errorf("iprange: invalid shorthand end '%s'", token)
+		}
+		return end, nil
+	}
+
+	v4 := start.To4()
+	if v4 == nil {
+		return nil, fmt.Errorf("iprange: invalid shorthand end '%s'", token)
+	}
+	octets := strings.Split(v4.String(), sep)
+	if len(tokenParts) > len(octets) {
+		return nil, fmt.Errorf("iprange: invalid shorthand end '%s'", token)
+	}
+	copy(octets[len(octets)-len(tokenParts):], tokenParts)
+	end := net.ParseIP(strings.Join(octets, sep))
+	if end == nil {
+		return nil, fmt.Errorf("iprange: invalid shorthand end '%s'", token)
+	}
+	return end, nil
+}
+
+// expandV6Groups returns the 8 hex groups of ip, uncompressed.
+func expandV6Groups(ip net.IP) []string {
+	ip16 := ip.To16()
+	groups := make([]string, 8)
+	for i := range groups {
+		groups[i] = strconv.FormatUint(uint64(ip16[i*2])<<8|uint64(ip16[i*2+1]), 16)
+	}
+	return groups
+}
+
+func parseCIDR(ipStr, maskStr string) (Range, error) {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return nil, fmt.Errorf("iprange: invalid address '%s'", ipStr)
+	}
+
+	var mask net.IPMask
+	if strings.Contains(maskStr, ".") {
+		maskIP := net.ParseIP(maskStr).To4()
+		if maskIP == nil {
+			return nil, fmt.Errorf("iprange: invalid subnet mask '%s'", maskStr)
+		}
+		mask = net.IPMask(maskIP)
+	} else {
+		prefixLen, err := strconv.Atoi(maskStr)
+		if err != nil {
+			return nil, fmt.Errorf("iprange: invalid prefix length '%s'", maskStr)
+		}
+		bits := 128
+		if ip.To4() != nil {
+			bits = 32
+		}
+		if prefixLen < 0 || prefixLen > bits {
+			return nil, fmt.Errorf("iprange: invalid prefix length '%s'", maskStr)
+		}
+		mask = net.CIDRMask(prefixLen, bits)
+	}
+
+	ones, bits := mask.Size()
+	if bits == 0 {
+		return nil, fmt.Errorf("iprange: invalid subnet mask '%s'", maskStr)
+	}
+
+	r := FromPrefix(net.IPNet{IP: ip, Mask: mask})
+	if r == nil {
+		return nil, fmt.Errorf("iprange: invalid CIDR '%s/%s'", ipStr, maskStr)
+	}
+
+	// IPv4 CIDR/subnet-mask forms exclude the network and broadcast
+	// addresses, except for /31 and /32 which keep both.
+	if r.Family() == V4Family && ones < 31 {
+		start, end := nextIP(r.Start()), prevIP(r.End())
+		if start == nil || end == nil {
+			return nil, fmt.Errorf("iprange: invalid CIDR '%s/%s'", ipStr, maskStr)
+		}
+		r = New(start, end)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		"v4 single address":      {input: "192.0.2.1", want: "192.0.2.1-192.0.2.1"},
+		"v6 single address":      {input: "2001:db8::1", want: "2001:db8::1-2001:db8::1"},
+		"v4 start-end":           {input: "192.0.2.0-192.0.2.10", want: "192.0.2.0-192.0.2.10"},
+		"v4 shorthand end":       {input: "192.0.2.1-10", want: "192.0.2.1-192.0.2.10"},
+		"v6 shorthand end":       {input: "2001:db8::1-a", want: "2001:db8::1-2001:db8::a"},
+		"v4 CIDR":                {input: "192.0.2.0/24", want: "192.0.2.1-192.0.2.254"},
+		"v4 dotted subnet mask":  {input: "192.0.2.0/255.255.255.0", want: "192.0.2.1-192.0.2.254"},
+		"v4 /31 keeps both ends": {input: "192.0.2.0/31", want: "192.0.2.0-192.0.2.1"},
+		"v4 /32 keeps both ends": {input: "192.0.2.0/32", want: "192.0.2.0-192.0.2.0"},
+		"v6 CIDR keeps all":      {input: "2001:db8::/126", want: "2001:db8::-2001:db8::3"},
+
+		"empty input":            {input: "", wantErr: true},
+		"bad octet":              {input: "192.0.2.999", wantErr: true},
+		"reversed start > end":   {input: "192.0.2.10-192.0.2.0", wantErr: true},
+		"out of range v4 prefix": {input: "192.0.2.0/33", wantErr: true},
+		"out of range v6 prefix": {input: "2001:db8::/129", wantErr: true},
+		"garbage":                {input: "not-an-ip", wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := ParseRange(test.input)
+			if test.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, r)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, r)
+			assert.Equal(t, test.want, r.String())
+		})
+	}
+}
+
+func TestParseRange_ErrorEchoesWholeToken(t *testing.T) {
+	_, err := ParseRange("not-an-ip")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not-an-ip")
+}
+
+func TestParseList(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		"comma separated": {
+			input: "192.0.2.1,192.0.2.2",
+			want:  []string{"192.0.2.1-192.0.2.1", "192.0.2.2-192.0.2.2"},
+		},
+		"whitespace separated": {
+			input: "192.0.2.1 192.0.2.2\t192.0.2.3\n2001:db8::1",
+			want:  []string{"192.0.2.1-192.0.2.1", "192.0.2.2-192.0.2.2", "192.0.2.3-192.0.2.3", "2001:db8::1-2001:db8::1"},
+		},
+		"mixed comma and whitespace": {
+			input: "192.0.2.0/31, 2001:db8::1-a",
+			want:  []string{"192.0.2.0-192.0.2.1", "2001:db8::1-2001:db8::a"},
+		},
+		"empty": {
+			input: "",
+			want:  nil,
+		},
+		"one bad entry fails the whole list": {
+			input:   "192.0.2.1,not-an-ip",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseList(test.input)
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var gotStr []string
+			for _, r := range got {
+				gotStr = append(gotStr, r.String())
+			}
+			assert.Equal(t, test.want, gotStr)
+		})
+	}
+}
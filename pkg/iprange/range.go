@@ -24,6 +24,17 @@ type Range interface {
 	Family() Family
 	Contains(ip net.IP) bool
 	Size() *big.Int
+	// Start returns the first IP address of the range.
+	Start() net.IP
+	// End returns the last IP address of the range.
+	End() net.IP
+	// Prefixes decomposes the range into the minimal set of CIDR blocks that cover it.
+	Prefixes() []net.IPNet
+	// EnumerableSize returns the number of addresses in the range, or an
+	// error if it exceeds MaxEnumerable.
+	EnumerableSize() (uint64, error)
+	// Iterator returns an Iterator over the range's addresses.
+	Iterator() Iterator
 	fmt.Stringer
 }
 
@@ -32,22 +43,45 @@ type Range interface {
 // New returns nil.
 func New(start, end net.IP) Range {
 	if isV4RangeValid(start, end) {
-		return v4Range{Start: start, End: end}
+		return v4Range{start: start.To4(), end: end.To4()}
 	}
 	if isV6RangeValid(start, end) {
-		return v6Range{Start: start, End: end}
+		return v6Range{start: start.To16(), end: end.To16()}
 	}
 	return nil
 }
 
+// FromPrefix returns the Range that exactly covers the given CIDR block.
+func FromPrefix(ipNet net.IPNet) Range {
+	start := ipNet.IP.Mask(ipNet.Mask)
+	end := make(net.IP, len(start))
+	for i := range start {
+		end[i] = start[i] | ^ipNet.Mask[i]
+	}
+	return New(start, end)
+}
+
+func isV4RangeValid(start, end net.IP) bool {
+	s, e := start.To4(), end.To4()
+	return s != nil && e != nil && bytes.Compare(s, e) <= 0
+}
+
+func isV6RangeValid(start, end net.IP) bool {
+	if start.To4() != nil || end.To4() != nil {
+		return false
+	}
+	s, e := start.To16(), end.To16()
+	return s != nil && e != nil && bytes.Compare(s, e) <= 0
+}
+
 type v4Range struct {
-	Start net.IP
-	End   net.IP
+	start net.IP
+	end   net.IP
 }
 
 // String returns the string form of the range.
 func (r v4Range) String() string {
-	return fmt.Sprintf("%s-%s", r.Start, r.End)
+	return fmt.Sprintf("%s-%s", r.start, r.end)
 }
 
 // Family returns the range address family.
@@ -57,22 +91,45 @@ func (r v4Range) Family() Family {
 
 // Contains reports whether the range includes IP.
 func (r v4Range) Contains(ip net.IP) bool {
-	return bytes.Compare(ip, r.Start) >= 0 && bytes.Compare(ip, r.End) <= 0
+	ip4 := ip.To4()
+	return ip4 != nil && bytes.Compare(ip4, r.start) >= 0 && bytes.Compare(ip4, r.end) <= 0
 }
 
 // Size reports the number of IP addresses in the range.
 func (r v4Range) Size() *big.Int {
-	return big.NewInt(v4ToInt(r.End) - v4ToInt(r.Start) + 1)
+	return big.NewInt(v4ToInt(r.end) - v4ToInt(r.start) + 1)
+}
+
+// Start returns the first IP address of the range.
+func (r v4Range) Start() net.IP { return r.start }
+
+// End returns the last IP address of the range.
+func (r v4Range) End() net.IP { return r.end }
+
+// Prefixes decomposes the range into the minimal set of CIDR blocks that cover it.
+func (r v4Range) Prefixes() []net.IPNet {
+	return decomposePrefixes(r.start, r.end, 32)
+}
+
+// EnumerableSize returns the number of addresses in the range, or an error
+// if it exceeds MaxEnumerable.
+func (r v4Range) EnumerableSize() (uint64, error) {
+	return enumerableSize(r.Size())
+}
+
+// Iterator returns an Iterator over the range's addresses.
+func (r v4Range) Iterator() Iterator {
+	return newIterator(r)
 }
 
 type v6Range struct {
-	Start net.IP
-	End   net.IP
+	start net.IP
+	end   net.IP
 }
 
 // String returns the string form of the range.
 func (r v6Range) String() string {
-	return fmt.Sprintf("%s-%s", r.Start, r.End)
+	return fmt.Sprintf("%s-%s", r.start, r.end)
 }
 
 // Family returns the range address family.
@@ -82,19 +139,92 @@ func (r v6Range) Family() Family {
 
 // Contains reports whether the range includes IP.
 func (r v6Range) Contains(ip net.IP) bool {
-	return bytes.Compare(ip, r.Start) >= 0 && bytes.Compare(ip, r.End) <= 0
+	ip16 := ip.To16()
+	return ip16 != nil && bytes.Compare(ip16, r.start) >= 0 && bytes.Compare(ip16, r.end) <= 0
 }
 
 // Size reports the number of IP addresses in the range.
 func (r v6Range) Size() *big.Int {
 	size := big.NewInt(0)
-	size.Add(size, big.NewInt(0).SetBytes(r.End))
-	size.Sub(size, big.NewInt(0).SetBytes(r.Start))
+	size.Add(size, big.NewInt(0).SetBytes(r.end))
+	size.Sub(size, big.NewInt(0).SetBytes(r.start))
 	size.Add(size, big.NewInt(1))
 	return size
 }
 
+// Start returns the first IP address of the range.
+func (r v6Range) Start() net.IP { return r.start }
+
+// End returns the last IP address of the range.
+func (r v6Range) End() net.IP { return r.end }
+
+// Prefixes decomposes the range into the minimal set of CIDR blocks that cover it.
+func (r v6Range) Prefixes() []net.IPNet {
+	return decomposePrefixes(r.start, r.end, 128)
+}
+
+// EnumerableSize returns the number of addresses in the range, or an error
+// if it exceeds MaxEnumerable.
+func (r v6Range) EnumerableSize() (uint64, error) {
+	return enumerableSize(r.Size())
+}
+
+// Iterator returns an Iterator over the range's addresses.
+func (r v6Range) Iterator() Iterator {
+	return newIterator(r)
+}
+
 func v4ToInt(ip net.IP) int64 {
 	ip = ip.To4()
 	return int64(ip[0])<<24 | int64(ip[1])<<16 | int64(ip[2])<<8 | int64(ip[3])
 }
+
+// decomposePrefixes splits the address range [start, end] into the minimal
+// set of CIDR blocks that exactly cover it. bits is the address width (32 for
+// IPv4, 128 for IPv6). At each step it emits the largest prefix aligned at
+// start whose end does not exceed end, then advances start past it.
+func decomposePrefixes(start, end net.IP, bits int) []net.IPNet {
+	cur := new(big.Int).SetBytes(start)
+	last := new(big.Int).SetBytes(end)
+	one := big.NewInt(1)
+
+	var prefixes []net.IPNet
+	for cur.Cmp(last) <= 0 {
+		// hostBits is the number of host bits of the largest block aligned at
+		// cur, shrunk until that block no longer runs past last.
+		hostBits := trailingZeroBits(cur, bits)
+		for hostBits > 0 {
+			blockEnd := new(big.Int).Lsh(one, uint(hostBits))
+			blockEnd.Add(blockEnd, cur)
+			blockEnd.Sub(blockEnd, one)
+			if blockEnd.Cmp(last) <= 0 {
+				break
+			}
+			hostBits--
+		}
+
+		ip := make(net.IP, bits/8)
+		curBytes := cur.Bytes()
+		copy(ip[len(ip)-len(curBytes):], curBytes)
+
+		prefixes = append(prefixes, net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(bits-hostBits, bits),
+		})
+
+		cur.Add(cur, new(big.Int).Lsh(one, uint(hostBits)))
+	}
+
+	return prefixes
+}
+
+// trailingZeroBits returns the number of trailing zero bits in n, treating n
+// as a bits-wide unsigned integer, capped at bits.
+func trailingZeroBits(n *big.Int, bits int) int {
+	for i := 0; i < bits; i++ {
+		if n.Bit(i) != 0 {
+			return i
+		}
+	}
+	return bits
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRange_Prefixes(t *testing.T) {
+	tests := map[string]struct {
+		start, end string
+		want       []string
+	}{
+		"unaligned v4 range": {
+			start: "192.0.2.0",
+			end:   "192.0.2.10",
+			want:  []string{"192.0.2.0/29", "192.0.2.8/31", "192.0.2.10/32"},
+		},
+		"single address": {
+			start: "192.0.2.5",
+			end:   "192.0.2.5",
+			want:  []string{"192.0.2.5/32"},
+		},
+		"full /24 block": {
+			start: "192.0.2.0",
+			end:   "192.0.2.255",
+			want:  []string{"192.0.2.0/24"},
+		},
+		"unaligned v6 range": {
+			start: "2001:db8::",
+			end:   "2001:db8::2",
+			want:  []string{"2001:db8::/127", "2001:db8::2/128"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := New(net.ParseIP(test.start), net.ParseIP(test.end))
+			require.NotNil(t, r)
+
+			var got []string
+			for _, p := range r.Prefixes() {
+				got = append(got, p.String())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestFromPrefix_PrefixesRoundTrip(t *testing.T) {
+	tests := map[string]string{
+		"v4 /24":  "192.0.2.0/24",
+		"v4 /32":  "192.0.2.7/32",
+		"v6 /64":  "2001:db8::/64",
+		"v6 /128": "2001:db8::1/128",
+	}
+
+	for name, cidr := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			require.NoError(t, err)
+
+			r := FromPrefix(*ipNet)
+			require.NotNil(t, r)
+
+			prefixes := r.Prefixes()
+			require.Len(t, prefixes, 1)
+			assert.Equal(t, cidr, prefixes[0].String())
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"bytes"
+	"math/big"
+	"net"
+	"sort"
+)
+
+// RangeSet is a sorted, canonicalized collection of Range values that
+// supports set-algebraic operations. The zero value is an empty set.
+type RangeSet struct {
+	ranges []Range
+}
+
+// NewRangeSet returns a RangeSet holding the given ranges, merged and sorted.
+func NewRangeSet(ranges ...Range) *RangeSet {
+	s := &RangeSet{ranges: append([]Range(nil), ranges...)}
+	s.ranges = mergeRanges(s.ranges)
+	return s
+}
+
+// Ranges returns the canonicalized ranges held by the set.
+func (s *RangeSet) Ranges() []Range {
+	return append([]Range(nil), s.ranges...)
+}
+
+// Size reports the total number of IP addresses covered by the set.
+func (s *RangeSet) Size() *big.Int {
+	size := big.NewInt(0)
+	for _, r := range s.ranges {
+		size.Add(size, r.Size())
+	}
+	return size
+}
+
+// Contains reports whether ip is covered by any range in the set.
+// It runs in O(log n) by binary searching the sorted, merged ranges of ip's
+// address family.
+func (s *RangeSet) Contains(ip net.IP) bool {
+	fam := ipFamily(ip)
+	lo := sort.Search(len(s.ranges), func(i int) bool { return s.ranges[i].Family() >= fam })
+	hi := sort.Search(len(s.ranges), func(i int) bool { return s.ranges[i].Family() > fam })
+	group := s.ranges[lo:hi]
+
+	i := sort.Search(len(group), func(i int) bool {
+		return bytes.Compare(normalizeIP(group[i].End()), normalizeIP(ip)) >= 0
+	})
+	return i < len(group) && group[i].Contains(ip)
+}
+
+// Merge returns a new RangeSet with overlapping and adjacent ranges coalesced.
+func (s *RangeSet) Merge() *RangeSet {
+	return &RangeSet{ranges: mergeRanges(s.ranges)}
+}
+
+// Union returns the set of addresses present in either s or other.
+func (s *RangeSet) Union(other *RangeSet) *RangeSet {
+	all := append(append([]Range(nil), s.ranges...), other.ranges...)
+	return &RangeSet{ranges: mergeRanges(all)}
+}
+
+// Intersect returns the set of addresses present in both s and other.
+func (s *RangeSet) Intersect(other *RangeSet) *RangeSet {
+	var out []Range
+	a, b := s.ranges, other.ranges
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i].Family() != b[j].Family() {
+			if a[i].Family() < b[j].Family() {
+				i++
+			} else {
+				j++
+			}
+			continue
+		}
+
+		start := maxIP(a[i].Start(), b[j].Start())
+		end := minIP(a[i].End(), b[j].End())
+		if bytes.Compare(normalizeIP(start), normalizeIP(end)) <= 0 {
+			out = append(out, New(start, end))
+		}
+
+		if bytes.Compare(normalizeIP(a[i].End()), normalizeIP(b[j].End())) < 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return &RangeSet{ranges: mergeRanges(out)}
+}
+
+// Diff returns the set of addresses present in s but not in other.
+func (s *RangeSet) Diff(other *RangeSet) *RangeSet {
+	var out []Range
+	for _, r := range s.ranges {
+		remaining := []Range{r}
+		for _, o := range other.ranges {
+			var next []Range
+			for _, rem := range remaining {
+				next = append(next, Subtract(rem, o)...)
+			}
+			remaining = next
+		}
+		out = append(out, remaining...)
+	}
+	return &RangeSet{ranges: mergeRanges(out)}
+}
+
+// mergeRanges sorts ranges by family and start address, then coalesces
+// overlapping or adjacent ranges within the same family.
+func mergeRanges(ranges []Range) []Range {
+	rs := append([]Range(nil), ranges...)
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Family() != rs[j].Family() {
+			return rs[i].Family() < rs[j].Family()
+		}
+		return bytes.Compare(normalizeIP(rs[i].Start()), normalizeIP(rs[j].Start())) < 0
+	})
+
+	var merged []Range
+	for _, r := range rs {
+		if len(merged) == 0 {
+			merged = append(merged, r)
+			continue
+		}
+
+		last := merged[len(merged)-1]
+		if !Contiguous(last, r) {
+			merged = append(merged, r)
+			continue
+		}
+
+		end := maxIP(last.End(), r.End())
+		merged[len(merged)-1] = New(last.Start(), end)
+	}
+	return merged
+}
+
+// ipFamily reports the address family of ip.
+func ipFamily(ip net.IP) Family {
+	if ip.To4() != nil {
+		return V4Family
+	}
+	return V6Family
+}
+
+// normalizeIP returns ip in its canonical 4- or 16-byte form so that
+// same-family addresses compare correctly with bytes.Compare.
+func normalizeIP(ip net.IP) net.IP {
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip.To16()
+}
+
+// maxIP returns the greater of a and b.
+func maxIP(a, b net.IP) net.IP {
+	if bytes.Compare(normalizeIP(a), normalizeIP(b)) >= 0 {
+		return a
+	}
+	return b
+}
+
+// minIP returns the lesser of a and b.
+func minIP(a, b net.IP) net.IP {
+	if bytes.Compare(normalizeIP(a), normalizeIP(b)) <= 0 {
+		return a
+	}
+	return b
+}
+
+// nextIP returns ip+1, or nil if ip is the highest address of its family.
+func nextIP(ip net.IP) net.IP {
+	out := append(net.IP(nil), normalizeIP(ip)...)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i]++
+		if out[i] != 0 {
+			return out
+		}
+	}
+	return nil
+}
+
+// prevIP returns ip-1, or nil if ip is the lowest address of its family.
+func prevIP(ip net.IP) net.IP {
+	out := append(net.IP(nil), normalizeIP(ip)...)
+	for i := len(out) - 1; i >= 0; i-- {
+		if out[i] != 0 {
+			out[i]--
+			return out
+		}
+		out[i] = 0xff
+	}
+	return nil
+}
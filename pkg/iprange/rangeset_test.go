@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package iprange
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseRange(t *testing.T, s string) Range {
+	t.Helper()
+	r, err := ParseRange(s)
+	if err != nil {
+		t.Fatalf("ParseRange(%q): %v", s, err)
+	}
+	return r
+}
+
+func rangeSetStrings(s *RangeSet) []string {
+	var out []string
+	for _, r := range s.Ranges() {
+		out = append(out, r.String())
+	}
+	return out
+}
+
+func TestRangeSet_Contains(t *testing.T) {
+	tests := map[string]struct {
+		ranges []string
+		ip     string
+		want   bool
+	}{
+		"v4 hit at lower boundary": {ranges: []string{"10.0.0.0-10.0.0.10"}, ip: "10.0.0.0", want: true},
+		"v4 hit at upper boundary": {ranges: []string{"10.0.0.0-10.0.0.10"}, ip: "10.0.0.10", want: true},
+		"v4 miss just below":       {ranges: []string{"10.0.0.1-10.0.0.10"}, ip: "10.0.0.0", want: false},
+		"v4 miss just above":       {ranges: []string{"10.0.0.0-10.0.0.10"}, ip: "10.0.0.11", want: false},
+		"v6 hit at lower boundary": {ranges: []string{"2001:db8::-2001:db8::ff"}, ip: "2001:db8::", want: true},
+		"v6 hit at upper boundary": {ranges: []string{"2001:db8::-2001:db8::ff"}, ip: "2001:db8::ff", want: true},
+		"v6 miss":                  {ranges: []string{"2001:db8::-2001:db8::ff"}, ip: "2001:db8::100", want: false},
+		"mixed family, v4 query":   {ranges: []string{"10.0.0.0-10.0.0.10", "2001:db8::-2001:db8::ff"}, ip: "10.0.0.5", want: true},
+		"mixed family, v6 query":   {ranges: []string{"10.0.0.0-10.0.0.10", "2001:db8::-2001:db8::ff"}, ip: "2001:db8::5", want: true},
+		"mixed family, no match":   {ranges: []string{"10.0.0.0-10.0.0.10", "2001:db8::-2001:db8::ff"}, ip: "10.0.0.50", want: false},
+		"empty set":                {ranges: nil, ip: "10.0.0.1", want: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var ranges []Range
+			for _, s := range test.ranges {
+				ranges = append(ranges, mustParseRange(t, s))
+			}
+			set := NewRangeSet(ranges...)
+			assert.Equal(t, test.want, set.Contains(net.ParseIP(test.ip)))
+		})
+	}
+}
+
+func TestRangeSet_Merge(t *testing.T) {
+	tests := map[string]struct {
+		ranges []string
+		want   []string
+	}{
+		"overlapping": {
+			ranges: []string{"10.0.0.0-10.0.0.10", "10.0.0.5-10.0.0.20"},
+			want:   []string{"10.0.0.0-10.0.0.20"},
+		},
+		"adjacent": {
+			ranges: []string{"10.0.0.0-10.0.0.10", "10.0.0.11-10.0.0.20"},
+			want:   []string{"10.0.0.0-10.0.0.20"},
+		},
+		"disjoint": {
+			ranges: []string{"10.0.0.0-10.0.0.10", "10.0.0.20-10.0.0.30"},
+			want:   []string{"10.0.0.0-10.0.0.10", "10.0.0.20-10.0.0.30"},
+		},
+		"fully contained": {
+			ranges: []string{"10.0.0.0-10.0.0.30", "10.0.0.10-10.0.0.20"},
+			want:   []string{"10.0.0.0-10.0.0.30"},
+		},
+		"empty": {
+			ranges: nil,
+			want:   nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var ranges []Range
+			for _, s := range test.ranges {
+				ranges = append(ranges, mustParseRange(t, s))
+			}
+			set := NewRangeSet(ranges...).Merge()
+			assert.Equal(t, test.want, rangeSetStrings(set))
+		})
+	}
+}
+
+func TestRangeSet_Union(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		want []string
+	}{
+		"overlapping": {
+			a:    []string{"10.0.0.0-10.0.0.10"},
+			b:    []string{"10.0.0.5-10.0.0.20"},
+			want: []string{"10.0.0.0-10.0.0.20"},
+		},
+		"adjacent": {
+			a:    []string{"10.0.0.0-10.0.0.10"},
+			b:    []string{"10.0.0.11-10.0.0.20"},
+			want: []string{"10.0.0.0-10.0.0.20"},
+		},
+		"disjoint": {
+			a:    []string{"10.0.0.0-10.0.0.10"},
+			b:    []string{"10.0.0.20-10.0.0.30"},
+			want: []string{"10.0.0.0-10.0.0.10", "10.0.0.20-10.0.0.30"},
+		},
+		"fully contained": {
+			a:    []string{"10.0.0.0-10.0.0.30"},
+			b:    []string{"10.0.0.10-10.0.0.20"},
+			want: []string{"10.0.0.0-10.0.0.30"},
+		},
+		"one side empty": {
+			a:    nil,
+			b:    []string{"10.0.0.0-10.0.0.10"},
+			want: []string{"10.0.0.0-10.0.0.10"},
+		},
+		"both empty": {
+			a:    nil,
+			b:    nil,
+			want: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var a, b []Range
+			for _, s := range test.a {
+				a = append(a, mustParseRange(t, s))
+			}
+			for _, s := range test.b {
+				b = append(b, mustParseRange(t, s))
+			}
+			got := NewRangeSet(a...).Union(NewRangeSet(b...))
+			assert.Equal(t, test.want, rangeSetStrings(got))
+		})
+	}
+}
+
+func TestRangeSet_Intersect(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		want []string
+	}{
+		"overlapping": {
+			a:    []string{"10.0.0.0-10.0.0.10"},
+			b:    []string{"10.0.0.5-10.0.0.20"},
+			want: []string{"10.0.0.5-10.0.0.10"},
+		},
+		"adjacent, no overlap": {
+			a:    []string{"10.0.0.0-10.0.0.10"},
+			b:    []string{"10.0.0.11-10.0.0.20"},
+			want: nil,
+		},
+		"disjoint": {
+			a:    []string{"10.0.0.0-10.0.0.10"},
+			b:    []string{"10.0.0.20-10.0.0.30"},
+			want: nil,
+		},
+		"fully contained": {
+			a:    []string{"10.0.0.0-10.0.0.30"},
+			b:    []string{"10.0.0.10-10.0.0.20"},
+			want: []string{"10.0.0.10-10.0.0.20"},
+		},
+		"empty set": {
+			a:    nil,
+			b:    []string{"10.0.0.0-10.0.0.10"},
+			want: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var a, b []Range
+			for _, s := range test.a {
+				a = append(a, mustParseRange(t, s))
+			}
+			for _, s := range test.b {
+				b = append(b, mustParseRange(t, s))
+			}
+			got := NewRangeSet(a...).Intersect(NewRangeSet(b...))
+			assert.Equal(t, test.want, rangeSetStrings(got))
+		})
+	}
+}
+
+func TestRangeSet_Diff(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		want []string
+	}{
+		"overlapping, trims tail": {
+			a:    []string{"10.0.0.0-10.0.0.10"},
+			b:    []string{"10.0.0.5-10.0.0.20"},
+			want: []string{"10.0.0.0-10.0.0.4"},
+		},
+		"adjacent, unchanged": {
+			a:    []string{"10.0.0.0-10.0.0.10"},
+			b:    []string{"10.0.0.11-10.0.0.20"},
+			want: []string{"10.0.0.0-10.0.0.10"},
+		},
+		"disjoint, unchanged": {
+			a:    []string{"10.0.0.0-10.0.0.10"},
+			b:    []string{"10.0.0.20-10.0.0.30"},
+			want: []string{"10.0.0.0-10.0.0.10"},
+		},
+		"b splits a in the middle": {
+			a:    []string{"10.0.0.0-10.0.0.30"},
+			b:    []string{"10.0.0.10-10.0.0.20"},
+			want: []string{"10.0.0.0-10.0.0.9", "10.0.0.21-10.0.0.30"},
+		},
+		"b fully covers a": {
+			a:    []string{"10.0.0.10-10.0.0.20"},
+			b:    []string{"10.0.0.0-10.0.0.30"},
+			want: nil,
+		},
+		"empty minuend": {
+			a:    nil,
+			b:    []string{"10.0.0.0-10.0.0.10"},
+			want: nil,
+		},
+		"empty subtrahend": {
+			a:    []string{"10.0.0.0-10.0.0.10"},
+			b:    nil,
+			want: []string{"10.0.0.0-10.0.0.10"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var a, b []Range
+			for _, s := range test.a {
+				a = append(a, mustParseRange(t, s))
+			}
+			for _, s := range test.b {
+				b = append(b, mustParseRange(t, s))
+			}
+			got := NewRangeSet(a...).Diff(NewRangeSet(b...))
+			assert.Equal(t, test.want, rangeSetStrings(got))
+		})
+	}
+}